@@ -0,0 +1,54 @@
+package ml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempCSV(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("unable to write fixture: %v", err)
+	}
+
+	return path
+}
+
+// TestLoadTargetColumnOutOfRangeReturnsError guards against values[:target]/
+// values[target+1:] panicking on an out-of-range TargetColumn
+func TestLoadTargetColumnOutOfRangeReturnsError(t *testing.T) {
+	path := writeTempCSV(t, "1,2,3\n4,5,6\n")
+
+	dl := NewDataLoader(DataLoaderOptions{Delimiter: ',', TargetColumn: 5})
+	if _, err := dl.Load(path); err == nil {
+		t.Fatal("Load with an out-of-range TargetColumn returned no error, want an error instead of a panic")
+	}
+}
+
+// TestStreamBatchesBoundedMemory checks that StreamBatches delivers all the
+// rows across several batches no larger than batchSize, without requiring
+// the caller to load the full file up front
+func TestStreamBatchesBoundedMemory(t *testing.T) {
+	path := writeTempCSV(t, "1,2,0\n3,4,1\n5,6,0\n7,8,1\n9,10,0\n")
+
+	dl := NewDataLoader(DataLoaderOptions{Delimiter: ',', TargetColumn: -1})
+
+	var totalRows int
+	err := dl.StreamBatches(path, 2, func(batch *Regression) error {
+		if len(batch.X) > 2 {
+			t.Fatalf("batch had %d rows, want at most 2", len(batch.X))
+		}
+		totalRows += len(batch.X)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamBatches returned an error: %v", err)
+	}
+
+	if totalRows != 5 {
+		t.Fatalf("StreamBatches delivered %d rows total, want 5", totalRows)
+	}
+}
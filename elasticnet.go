@@ -0,0 +1,89 @@
+package ml
+
+import "fmt"
+
+// sign returns -1, 0 or 1 depending on the sign of v, used as the
+// subgradient of the L1 penalty at points other than the origin
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// softThreshold is the proximal operator of the L1 penalty:
+// soft(z, t) = sign(z) * max(|z|-t, 0)
+func softThreshold(z, t float64) float64 {
+	switch {
+	case z > t:
+		return z - t
+	case z < -t:
+		return z + t
+	default:
+		return 0
+	}
+}
+
+// CoordinateDescentOptimizer trains a linear Regression under elastic-net
+// regularization using cyclic coordinate descent: every theta is updated in
+// turn by taking a gradient step on the smooth (squared error + L2) part of
+// the cost and then applying soft-thresholding for the non-differentiable L1
+// part, theta_j <- soft(theta_j - eta*dJ/dtheta_j, eta*lambda*L1Ratio/m),
+// with m the number of test cases, matching the lambda/m scaling of
+// CostFunction. The bias theta (index 0) is not thresholded
+type CoordinateDescentOptimizer struct{}
+
+// Optimize runs cyclic coordinate descent against rg.Theta. It only supports
+// linear regression, since the per-coordinate update below assumes a
+// quadratic (squared error) loss
+func (o *CoordinateDescentOptimizer) Optimize(rg *Regression, opts *OptimizerOptions) (err error) {
+	if !rg.LinearReg {
+		return fmt.Errorf("go_ml: CoordinateDescentOptimizer only supports linear regression")
+	}
+
+	m := float64(len(rg.X))
+
+	pred := make([]float64, len(rg.X))
+	for i, x := range rg.X {
+		pred[i] = rg.LinearHipotesis(x)
+	}
+
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		eta := learningRate(opts, epoch)
+
+		for j := range rg.Theta {
+			gradJ := 0.0
+			for i, x := range rg.X {
+				gradJ += (pred[i] - rg.Y[i]) * x[j]
+			}
+			gradJ /= m
+			if j > 0 {
+				gradJ += (opts.Lambda * (1 - rg.L1Ratio) / m) * rg.Theta[j]
+			}
+
+			old := rg.Theta[j]
+			z := old - eta*gradJ
+
+			var updated float64
+			if j == 0 {
+				updated = z
+			} else {
+				updated = softThreshold(z, eta*opts.Lambda*rg.L1Ratio/m)
+			}
+
+			delta := updated - old
+			rg.Theta[j] = updated
+			for i, x := range rg.X {
+				pred[i] += delta * x[j]
+			}
+		}
+
+		reportEpoch(rg, opts, epoch)
+	}
+
+	return
+}
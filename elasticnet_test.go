@@ -0,0 +1,60 @@
+package ml
+
+import "testing"
+
+// TestCoordinateDescentOptimizerFitsRidge checks that cyclic coordinate
+// descent with L1Ratio 0 (pure ridge) drives the cost down like the other
+// optimizers on a tiny, exactly-fittable linear regression problem
+func TestCoordinateDescentOptimizerFitsRidge(t *testing.T) {
+	rg := &Regression{
+		X:         [][]float64{{1, 1}, {1, 2}, {1, 3}, {1, 4}},
+		Y:         []float64{3, 5, 7, 9}, // y = 2x + 1
+		Theta:     []float64{0, 0},
+		LinearReg: true,
+	}
+
+	opt := &CoordinateDescentOptimizer{}
+	opts := &OptimizerOptions{LearningRate: 0.1, Epochs: 500, Lambda: 0}
+	if err := opt.Optimize(rg, opts); err != nil {
+		t.Fatalf("Optimize returned an error: %v", err)
+	}
+
+	finalCost, _, _ := rg.CostFunction(0, false)
+	if finalCost > 0.01 {
+		t.Fatalf("CoordinateDescentOptimizer left cost at %v, want it close to 0", finalCost)
+	}
+}
+
+// TestCoordinateDescentOptimizerRejectsLogistic guards the documented
+// limitation that coordinate descent here only supports linear regression
+func TestCoordinateDescentOptimizerRejectsLogistic(t *testing.T) {
+	rg := &Regression{
+		X:         [][]float64{{1, 1}, {1, 2}},
+		Y:         []float64{0, 1},
+		Theta:     []float64{0, 0},
+		LinearReg: false,
+	}
+
+	opt := &CoordinateDescentOptimizer{}
+	if err := opt.Optimize(rg, &OptimizerOptions{LearningRate: 0.1, Epochs: 1}); err == nil {
+		t.Fatal("Optimize on a logistic Regression returned no error, want one")
+	}
+}
+
+// TestSoftThreshold checks the elastic-net proximal operator directly
+func TestSoftThreshold(t *testing.T) {
+	cases := []struct {
+		z, thresh, want float64
+	}{
+		{2, 0.5, 1.5},
+		{-2, 0.5, -1.5},
+		{0.3, 0.5, 0},
+		{-0.3, 0.5, 0},
+	}
+
+	for _, c := range cases {
+		if got := softThreshold(c.z, c.thresh); got != c.want {
+			t.Errorf("softThreshold(%v, %v) = %v, want %v", c.z, c.thresh, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package ml
+
+// PolynomialFeatures expands X with polynomial and interaction terms up to
+// the given degree, analogous to sklearn's PolynomialFeatures transformer.
+// The original features (degree 1) are always included. When interactionOnly
+// is true, pure powers of a single feature (e.g. x1^2) are skipped and only
+// products of distinct features (e.g. x1*x2) are generated
+func PolynomialFeatures(X [][]float64, degree int, interactionOnly bool) [][]float64 {
+	result := make([][]float64, len(X))
+	for i, row := range X {
+		result[i] = expandRow(row, degree, interactionOnly)
+	}
+
+	return result
+}
+
+// expandRow generates every polynomial/interaction term of row up to degree
+func expandRow(row []float64, degree int, interactionOnly bool) []float64 {
+	var terms []float64
+
+	var gen func(start, remaining int, product float64)
+	gen = func(start, remaining int, product float64) {
+		if remaining == 0 {
+			terms = append(terms, product)
+			return
+		}
+
+		for i := start; i < len(row); i++ {
+			nextStart := i
+			if interactionOnly {
+				nextStart = i + 1
+			}
+			gen(nextStart, remaining-1, product*row[i])
+		}
+	}
+
+	for d := 1; d <= degree; d++ {
+		gen(0, d, 1.0)
+	}
+
+	return terms
+}
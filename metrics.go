@@ -0,0 +1,197 @@
+package ml
+
+import (
+	"math"
+	"sort"
+)
+
+// confusionCounts classifies every test case in rg.X/rg.Y against a 0.5
+// threshold on the model's hipothesis and returns the true positive, false
+// positive, true negative and false negative counts
+func (rg *Regression) confusionCounts() (tp, fp, tn, fn float64) {
+	for i, x := range rg.X {
+		y := rg.Y[i]
+
+		var h float64
+		if rg.LinearReg {
+			h = rg.LinearHipotesis(x)
+		} else {
+			h = rg.LogisticHipotesis(x)
+		}
+
+		predicted := 0.0
+		if h >= 0.5 {
+			predicted = 1
+		}
+
+		switch {
+		case predicted == 1 && y == 1:
+			tp++
+		case predicted == 1 && y == 0:
+			fp++
+		case predicted == 0 && y == 0:
+			tn++
+		case predicted == 0 && y == 1:
+			fn++
+		}
+	}
+
+	return
+}
+
+// Precision returns tp / (tp + fp) for a 0.5-threshold classification of the
+// test cases in rg.X/rg.Y, 0 when no positive prediction was made
+func (rg *Regression) Precision() float64 {
+	tp, fp, _, _ := rg.confusionCounts()
+	if tp+fp == 0 {
+		return 0
+	}
+
+	return tp / (tp + fp)
+}
+
+// Recall returns tp / (tp + fn) for a 0.5-threshold classification of the
+// test cases in rg.X/rg.Y, 0 when there are no positive cases
+func (rg *Regression) Recall() float64 {
+	tp, _, _, fn := rg.confusionCounts()
+	if tp+fn == 0 {
+		return 0
+	}
+
+	return tp / (tp + fn)
+}
+
+// F1Score returns the harmonic mean of Precision and Recall, 0 when both are 0
+func (rg *Regression) F1Score() float64 {
+	p := rg.Precision()
+	r := rg.Recall()
+	if p+r == 0 {
+		return 0
+	}
+
+	return 2 * p * r / (p + r)
+}
+
+// AUC computes the area under the ROC curve of the LogisticHipotesis scores
+// against rg.Y, using the Mann-Whitney U statistic. It returns 0 when rg.Y
+// contains only one class, since the AUC is undefined in that case
+func (rg *Regression) AUC() float64 {
+	type scored struct {
+		score float64
+		y     float64
+	}
+
+	scores := make([]scored, len(rg.X))
+	pos, neg := 0.0, 0.0
+	for i, x := range rg.X {
+		scores[i] = scored{score: rg.LogisticHipotesis(x), y: rg.Y[i]}
+		if rg.Y[i] == 1 {
+			pos++
+		} else {
+			neg++
+		}
+	}
+
+	if pos == 0 || neg == 0 {
+		return 0
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].score < scores[j].score })
+
+	rankSum := 0.0
+	for i, s := range scores {
+		if s.y == 1 {
+			rankSum += float64(i + 1)
+		}
+	}
+
+	u := rankSum - pos*(pos+1)/2
+
+	return u / (pos * neg)
+}
+
+// RSquared returns the coefficient of determination of the LinearHipotesis
+// predictions against rg.Y: 1 - SSres/SStot, 0 when Y has zero variance
+func (rg *Regression) RSquared() float64 {
+	meanY := 0.0
+	for _, y := range rg.Y {
+		meanY += y
+	}
+	meanY /= float64(len(rg.Y))
+
+	ssRes, ssTot := 0.0, 0.0
+	for i, x := range rg.X {
+		pred := rg.LinearHipotesis(x)
+		ssRes += (rg.Y[i] - pred) * (rg.Y[i] - pred)
+		ssTot += (rg.Y[i] - meanY) * (rg.Y[i] - meanY)
+	}
+
+	if ssTot == 0 {
+		return 0
+	}
+
+	return 1 - ssRes/ssTot
+}
+
+// score returns a "higher is better" performance metric appropriate for the
+// instance's mode: RSquared for linear regression, classification Accuracy
+// for logistic regression. MinimizeCost and KFoldCV use it to pick between
+// candidate lambdas regardless of which mode the Regression is in
+func (rg *Regression) score() float64 {
+	if rg.LinearReg {
+		return rg.RSquared()
+	}
+
+	return rg.Accuracy()
+}
+
+// KFoldCV performs k-fold cross validation over the candidate lambdas and
+// returns the lambda with the best average score() across the folds
+// (accuracy for logistic regression, RSquared for linear regression)
+func (rg *Regression) KFoldCV(k int, lambdas []float64, maxIters int, verbose bool) (bestLambda float64) {
+	shuffled := rg.shuffle()
+	foldSize := len(shuffled.X) / k
+	bestAvgScore := math.Inf(-1)
+
+	for _, lambda := range lambdas {
+		totalScore := 0.0
+
+		for fold := 0; fold < k; fold++ {
+			start := fold * foldSize
+			end := start + foldSize
+			if fold == k-1 {
+				end = len(shuffled.X)
+			}
+
+			trainX := append(append([][]float64{}, shuffled.X[:start]...), shuffled.X[end:]...)
+			trainY := append(append([]float64{}, shuffled.Y[:start]...), shuffled.Y[end:]...)
+
+			trainFold := &Regression{
+				X:             trainX,
+				Y:             trainY,
+				LinearReg:     shuffled.LinearReg,
+				L1Ratio:       shuffled.L1Ratio,
+				Optimizer:     shuffled.Optimizer,
+				OptimizerOpts: shuffled.OptimizerOpts,
+			}
+			trainFold.Theta = make([]float64, len(shuffled.Theta))
+			fit(trainFold, lambda, maxIters, verbose)
+
+			valFold := &Regression{
+				X:         shuffled.X[start:end],
+				Y:         shuffled.Y[start:end],
+				Theta:     trainFold.Theta,
+				LinearReg: shuffled.LinearReg,
+			}
+			totalScore += valFold.score()
+		}
+
+		avgScore := totalScore / float64(k)
+		if avgScore > bestAvgScore {
+			bestAvgScore = avgScore
+			bestLambda = lambda
+		}
+	}
+
+	return
+}
@@ -0,0 +1,38 @@
+package ml
+
+import "testing"
+
+// TestScoreUsesRSquaredForLinearRegression guards against score() (used by
+// MinimizeCost/KFoldCV to pick the best lambda) silently calling Accuracy on
+// a linear-regression instance, where y == 1 essentially never holds
+func TestScoreUsesRSquaredForLinearRegression(t *testing.T) {
+	rg := &Regression{
+		X:         [][]float64{{1, 1}, {1, 2}, {1, 3}, {1, 4}},
+		Y:         []float64{3, 5, 7, 9}, // y = 2x + 1, exactly fit by Theta below
+		Theta:     []float64{1, 2},
+		LinearReg: true,
+	}
+
+	if got := rg.Accuracy(); got != 0 {
+		t.Fatalf("Accuracy() on a perfectly-fit linear model = %v, want 0 (Y holds continuous values, never 0 or 1)", got)
+	}
+
+	if got := rg.score(); got < 0.99 {
+		t.Fatalf("score() on a perfectly-fit linear model = %v, want ~1 (RSquared)", got)
+	}
+}
+
+// TestAccuracyCountsTrueNegatives guards against Accuracy() only rewarding
+// predicted-positive matches, which would make it reward lambdas that
+// predict more positives irrespective of negative-class performance
+func TestAccuracyCountsTrueNegatives(t *testing.T) {
+	rg := &Regression{
+		X:     [][]float64{{1, 0}, {1, 0}, {1, 10}, {1, 10}},
+		Y:     []float64{0, 0, 1, 1},
+		Theta: []float64{-5, 1},
+	}
+
+	if got := rg.Accuracy(); got != 1 {
+		t.Fatalf("Accuracy() on a perfectly-separating classifier = %v, want 1", got)
+	}
+}
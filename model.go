@@ -0,0 +1,60 @@
+package ml
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// savedModel is the JSON-serializable representation of a trained
+// Regression, persisting the theta coefficients, scaling parameters and the
+// linear vs. logistic mode so a trained model can be restored without
+// retraining
+type savedModel struct {
+	Theta     []float64      `json:"theta"`
+	LinearReg bool           `json:"linear_reg"`
+	L1Ratio   float64        `json:"l1_ratio,omitempty"`
+	Scaler    *FeatureScaler `json:"scaler,omitempty"`
+}
+
+// SaveModel serializes the trained Theta, scaling parameters, and
+// regression mode of rg to filePath as JSON
+func SaveModel(rg *Regression, filePath string) error {
+	data, err := json.MarshalIndent(savedModel{
+		Theta:     rg.Theta,
+		LinearReg: rg.LinearReg,
+		L1Ratio:   rg.L1Ratio,
+		Scaler:    rg.Scaler,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("go_ml: unable to encode model: %w", err)
+	}
+
+	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("go_ml: unable to write model to %q: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// LoadModel deserializes a Regression previously persisted with SaveModel
+func LoadModel(filePath string) (rg *Regression, err error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("go_ml: unable to read model from %q: %w", filePath, err)
+	}
+
+	var model savedModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("go_ml: unable to decode model from %q: %w", filePath, err)
+	}
+
+	rg = &Regression{
+		Theta:     model.Theta,
+		LinearReg: model.LinearReg,
+		L1Ratio:   model.L1Ratio,
+		Scaler:    model.Scaler,
+	}
+
+	return rg, nil
+}
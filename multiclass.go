@@ -0,0 +1,211 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	mt "github.com/alonsovidales/go_matrix"
+)
+
+// MultiRegression Multiclass logistic regression structure, the natural
+// extension of Regression for problems where Y takes one of several
+// discrete class labels (0..NumClasses-1) instead of a single binary value.
+type MultiRegression struct {
+	X [][]float64 // Training set of values for each feature, the first dimension are the test cases
+	Y []float64   // The training set with the class label (0..NumClasses-1) for each test case
+	// 1st dim -> class, 2nd dim -> theta
+	Theta      [][]float64
+	NumClasses int
+}
+
+// InitializeTheta Initialize the Theta property to a NumClasses x numFeatures
+// matrix of zeros
+func (mrg *MultiRegression) InitializeTheta(numFeatures, numClasses int) {
+	rand.Seed(int64(time.Now().Nanosecond()))
+
+	mrg.NumClasses = numClasses
+	mrg.Theta = make([][]float64, numClasses)
+	for c := range mrg.Theta {
+		mrg.Theta[c] = make([]float64, numFeatures)
+	}
+}
+
+// CostFunction Calculates the categorical cross-entropy cost for the
+// training set stored in the X and Y properties of the instance, and with
+// the theta configuration, using a Softmax hipothesis. The lambda parameter
+// controls the degree of L2 regularization, applied to every non-bias
+// parameter. The calcGrad param in case of true calculates the gradient in
+// addition of the cost, and in case of false, only calculates the cost
+func (mrg *MultiRegression) CostFunction(lambda float64, calcGrad bool) (j float64, grad [][]float64, err error) {
+	if len(mrg.Y) != len(mrg.X) {
+		err = fmt.Errorf(
+			"the number of test cases (X) %d doesn't corresponds with the number of values (Y) %d",
+			len(mrg.X),
+			len(mrg.Y))
+		return
+	}
+
+	if len(mrg.Theta) != mrg.NumClasses {
+		err = fmt.Errorf(
+			"the Theta arg has %d rows and NumClasses is %d",
+			len(mrg.Theta),
+			mrg.NumClasses)
+		return
+	}
+
+	m := float64(len(mrg.X))
+	scores := mt.Mult(mrg.X, mt.Trans(mrg.Theta))
+	probs := softmax(scores)
+
+	for i, y := range mrg.Y {
+		p := probs[i][int(y)]
+		if p < 1e-15 {
+			p = 1e-15
+		}
+		j -= math.Log(p)
+	}
+	j /= m
+
+	regSum := 0.0
+	for _, theta := range mrg.Theta {
+		for f := 1; f < len(theta); f++ {
+			regSum += theta[f] * theta[f]
+		}
+	}
+	j += (lambda / (2 * m)) * regSum
+
+	if !calcGrad {
+		return
+	}
+
+	yOneHot := make([][]float64, len(mrg.Y))
+	for i, y := range mrg.Y {
+		row := make([]float64, mrg.NumClasses)
+		row[int(y)] = 1
+		yOneHot[i] = row
+	}
+
+	regTheta := make([][]float64, len(mrg.Theta))
+	for c, theta := range mrg.Theta {
+		row := make([]float64, len(theta))
+		copy(row, theta)
+		row[0] = 0
+		regTheta[c] = row
+	}
+
+	grad = mt.Sum(
+		mt.MultBy(mt.Mult(mt.Trans(mt.Sub(probs, yOneHot)), mrg.X), 1/m),
+		mt.MultBy(regTheta, lambda/m))
+
+	return
+}
+
+// softmax applies the softmax function row-wise to the given m x k score
+// matrix, returning the class probabilities for each test case
+func softmax(scores [][]float64) [][]float64 {
+	probs := make([][]float64, len(scores))
+	for i, row := range scores {
+		max := row[0]
+		for _, v := range row {
+			if v > max {
+				max = v
+			}
+		}
+
+		sum := 0.0
+		exp := make([]float64, len(row))
+		for c, v := range row {
+			exp[c] = math.Exp(v - max)
+			sum += exp[c]
+		}
+
+		probs[i] = make([]float64, len(row))
+		for c := range exp {
+			probs[i][c] = exp[c] / sum
+		}
+	}
+
+	return probs
+}
+
+// Train fits mrg.Theta directly against the multinomial Softmax cost via
+// batch gradient descent, using the gradient computed by CostFunction. This
+// is the training path for the actual softmax model; OneVsAll is a separate,
+// simpler alternative that approximates it with K independent binary models
+func (mrg *MultiRegression) Train(lambda, alpha float64, maxIters int, verbose bool) (finalCost float64, err error) {
+	for iter := 0; iter < maxIters; iter++ {
+		j, grad, cerr := mrg.CostFunction(lambda, true)
+		if cerr != nil {
+			return 0, cerr
+		}
+
+		for c := range mrg.Theta {
+			for f := range mrg.Theta[c] {
+				mrg.Theta[c][f] -= alpha * grad[c][f]
+			}
+		}
+
+		if verbose {
+			fmt.Println("Iter:", iter, "Cost:", j)
+		}
+
+		finalCost = j
+	}
+
+	return finalCost, nil
+}
+
+// OneVsAll trains numClasses independent logistic regressions, one per
+// class label, via the existing Fmincg loop, and stores the resulting
+// thetas on the instance so Predict can be used afterwards
+func (mrg *MultiRegression) OneVsAll(numClasses int, lambda float64, maxIters int, verbose bool) (classifiers []*Regression) {
+	classifiers = make([]*Regression, numClasses)
+
+	for c := 0; c < numClasses; c++ {
+		binY := make([]float64, len(mrg.Y))
+		for i, y := range mrg.Y {
+			if int(y) == c {
+				binY[i] = 1
+			}
+		}
+
+		rg := &Regression{X: mrg.X, Y: binY, LinearReg: false}
+		rg.InitializeTheta()
+		Fmincg(rg, lambda, maxIters, verbose)
+		classifiers[c] = rg
+	}
+
+	mrg.NumClasses = numClasses
+	mrg.Theta = make([][]float64, numClasses)
+	for c, rg := range classifiers {
+		mrg.Theta[c] = rg.Theta
+	}
+
+	return
+}
+
+// Predict returns the argmax class for x along with the full probability
+// vector produced by the Softmax hipothesis
+func (mrg *MultiRegression) Predict(x []float64) (class int, probs []float64) {
+	scores := make([]float64, mrg.NumClasses)
+	for c, theta := range mrg.Theta {
+		s := 0.0
+		for i := range x {
+			s += x[i] * theta[i]
+		}
+		scores[c] = s
+	}
+
+	probs = softmax([][]float64{scores})[0]
+
+	class = 0
+	for c := 1; c < len(probs); c++ {
+		if probs[c] > probs[class] {
+			class = c
+		}
+	}
+
+	return
+}
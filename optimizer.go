@@ -0,0 +1,229 @@
+package ml
+
+import (
+	"fmt"
+	"math"
+)
+
+// LRSchedule controls how the learning rate evolves across epochs for the
+// mini-batch optimizers
+type LRSchedule int
+
+const (
+	// ConstantLR keeps the learning rate fixed for every epoch
+	ConstantLR LRSchedule = iota
+	// InverseDecayLR applies eta / (1 + decay * epoch)
+	InverseDecayLR
+	// StepDecayLR multiplies the learning rate by Decay every StepSize epochs
+	StepDecayLR
+)
+
+// OptimizerOptions configures a mini-batch optimizer run
+type OptimizerOptions struct {
+	Lambda       float64
+	BatchSize    int
+	Epochs       int
+	LearningRate float64
+	Schedule     LRSchedule
+	Decay        float64 // used by InverseDecayLR and StepDecayLR
+	StepSize     int     // epochs between decay steps, used by StepDecayLR
+	Shuffle      bool
+	Momentum     float64 // used by SGDOptimizer, 0 disables momentum
+	Beta1        float64 // used by AdamOptimizer, defaults to 0.9 when 0
+	Beta2        float64 // used by AdamOptimizer, defaults to 0.999 when 0
+	Epsilon      float64 // used by AdamOptimizer, defaults to 1e-8 when 0
+	Verbose      bool
+	// OnEpoch, when set, is called after every epoch with the cost and
+	// score (see Regression.score) measured against the full training set
+	OnEpoch func(epoch int, cost float64, score float64)
+}
+
+// Optimizer trains a Regression instance in place, as an alternative to the
+// full-batch conjugate-gradient Fmincg loop
+type Optimizer interface {
+	Optimize(rg *Regression, opts *OptimizerOptions) error
+}
+
+// fit trains rg against the given lambda: it runs rg.Optimizer (configured
+// from rg.OptimizerOpts, with Lambda/Epochs/Verbose overridden for this
+// call) when set, or falls back to the conjugate-gradient Fmincg loop
+// otherwise. MinimizeCost and KFoldCV both go through fit, so a Regression
+// can opt into mini-batch training by just setting Optimizer/OptimizerOpts
+func fit(rg *Regression, lambda float64, maxIters int, verbose bool) {
+	if rg.Optimizer == nil {
+		Fmincg(rg, lambda, maxIters, verbose)
+		return
+	}
+
+	opts := &OptimizerOptions{}
+	if rg.OptimizerOpts != nil {
+		withLambda := *rg.OptimizerOpts
+		opts = &withLambda
+	}
+	opts.Lambda = lambda
+	opts.Epochs = maxIters
+	opts.Verbose = verbose
+
+	if err := rg.Optimizer.Optimize(rg, opts); err != nil && verbose {
+		fmt.Println("Optimizer error:", err)
+	}
+}
+
+// SGDOptimizer trains a Regression using classic mini-batch gradient descent
+// with momentum: v <- momentum*v - learningRate*grad; theta <- theta + v
+type SGDOptimizer struct{}
+
+// Optimize runs mini-batch SGD with momentum against rg.Theta
+func (o *SGDOptimizer) Optimize(rg *Regression, opts *OptimizerOptions) (err error) {
+	if opts.BatchSize <= 0 {
+		return fmt.Errorf("the BatchSize option must be greater than 0")
+	}
+
+	velocity := make([]float64, len(rg.Theta))
+
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		data := rg
+		if opts.Shuffle {
+			data = rg.shuffle()
+		}
+
+		lr := learningRate(opts, epoch)
+		for _, batch := range splitBatches(data, opts.BatchSize) {
+			_, grad, gerr := batch.CostFunction(opts.Lambda, true)
+			if gerr != nil {
+				return gerr
+			}
+
+			g := grad[0][0]
+			for i := range rg.Theta {
+				velocity[i] = opts.Momentum*velocity[i] - lr*g[i]
+				rg.Theta[i] += velocity[i]
+			}
+		}
+
+		reportEpoch(rg, opts, epoch)
+	}
+
+	return
+}
+
+// AdamOptimizer trains a Regression using the Adam optimizer, tracking
+// exponential moving averages of the gradient (m) and its square (v), with
+// bias correction applied at every step
+type AdamOptimizer struct{}
+
+// Optimize runs mini-batch Adam against rg.Theta
+func (o *AdamOptimizer) Optimize(rg *Regression, opts *OptimizerOptions) (err error) {
+	if opts.BatchSize <= 0 {
+		return fmt.Errorf("the BatchSize option must be greater than 0")
+	}
+
+	beta1, beta2, epsilon := opts.Beta1, opts.Beta2, opts.Epsilon
+	if beta1 == 0 {
+		beta1 = 0.9
+	}
+	if beta2 == 0 {
+		beta2 = 0.999
+	}
+	if epsilon == 0 {
+		epsilon = 1e-8
+	}
+
+	m := make([]float64, len(rg.Theta))
+	v := make([]float64, len(rg.Theta))
+	t := 0
+
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		data := rg
+		if opts.Shuffle {
+			data = rg.shuffle()
+		}
+
+		lr := learningRate(opts, epoch)
+		for _, batch := range splitBatches(data, opts.BatchSize) {
+			t++
+
+			_, grad, gerr := batch.CostFunction(opts.Lambda, true)
+			if gerr != nil {
+				return gerr
+			}
+
+			g := grad[0][0]
+			for i := range rg.Theta {
+				m[i] = beta1*m[i] + (1-beta1)*g[i]
+				v[i] = beta2*v[i] + (1-beta2)*g[i]*g[i]
+
+				mHat := m[i] / (1 - math.Pow(beta1, float64(t)))
+				vHat := v[i] / (1 - math.Pow(beta2, float64(t)))
+
+				rg.Theta[i] -= lr * mHat / (math.Sqrt(vHat) + epsilon)
+			}
+		}
+
+		reportEpoch(rg, opts, epoch)
+	}
+
+	return
+}
+
+// learningRate returns the learning rate for the given epoch, following the
+// schedule configured in opts
+func learningRate(opts *OptimizerOptions, epoch int) float64 {
+	switch opts.Schedule {
+	case InverseDecayLR:
+		return opts.LearningRate / (1 + opts.Decay*float64(epoch))
+	case StepDecayLR:
+		if opts.StepSize <= 0 {
+			return opts.LearningRate
+		}
+		steps := epoch / opts.StepSize
+		return opts.LearningRate * math.Pow(opts.Decay, float64(steps))
+	default:
+		return opts.LearningRate
+	}
+}
+
+// splitBatches partitions rg.X/rg.Y into Regression instances of at most
+// batchSize rows each, sharing the same Theta slice so in-place updates are
+// immediately visible to every batch
+func splitBatches(rg *Regression, batchSize int) (batches []*Regression) {
+	for start := 0; start < len(rg.X); start += batchSize {
+		end := start + batchSize
+		if end > len(rg.X) {
+			end = len(rg.X)
+		}
+
+		batches = append(batches, &Regression{
+			X:         rg.X[start:end],
+			Y:         rg.Y[start:end],
+			Theta:     rg.Theta,
+			LinearReg: rg.LinearReg,
+			L1Ratio:   rg.L1Ratio,
+			Scaler:    rg.Scaler,
+		})
+	}
+
+	return
+}
+
+// reportEpoch measures cost/score against the full training set and invokes
+// opts.OnEpoch when the caller asked for per-epoch feedback. score() is
+// mode-aware (Accuracy for logistic regression, RSquared for linear), so the
+// reported figure is meaningful for either mode, including the
+// CoordinateDescentOptimizer, which only ever trains linear regressions
+func reportEpoch(rg *Regression, opts *OptimizerOptions, epoch int) {
+	if opts.OnEpoch == nil && !opts.Verbose {
+		return
+	}
+
+	cost, _, _ := rg.CostFunction(opts.Lambda, false)
+	score := rg.score()
+
+	if opts.Verbose {
+		fmt.Println("Epoch:", epoch, "Cost:", cost, "Score:", score)
+	}
+
+	if opts.OnEpoch != nil {
+		opts.OnEpoch(epoch, cost, score)
+	}
+}
@@ -0,0 +1,111 @@
+package ml
+
+import (
+	"math"
+	"testing"
+)
+
+type spyOptimizer struct {
+	called bool
+}
+
+func (o *spyOptimizer) Optimize(rg *Regression, opts *OptimizerOptions) error {
+	o.called = true
+	return nil
+}
+
+// TestFitUsesConfiguredOptimizer guards against MinimizeCost/KFoldCV (via
+// fit) ignoring rg.Optimizer and always falling back to Fmincg
+func TestFitUsesConfiguredOptimizer(t *testing.T) {
+	spy := &spyOptimizer{}
+	rg := &Regression{
+		X:         [][]float64{{1, 1}, {1, 2}},
+		Y:         []float64{1, 0},
+		Theta:     []float64{0, 0},
+		Optimizer: spy,
+	}
+
+	fit(rg, 0.1, 5, false)
+
+	if !spy.called {
+		t.Fatal("fit() did not call the configured Optimizer")
+	}
+}
+
+// TestSGDOptimizerFitsLinearRegression checks that mini-batch SGD with
+// momentum actually drives the cost down on a tiny linear regression problem
+func TestSGDOptimizerFitsLinearRegression(t *testing.T) {
+	rg := &Regression{
+		X:         [][]float64{{1, 1}, {1, 2}, {1, 3}, {1, 4}},
+		Y:         []float64{3, 5, 7, 9}, // y = 2x + 1
+		Theta:     []float64{0, 0},
+		LinearReg: true,
+	}
+
+	initialCost, _, _ := rg.CostFunction(0, false)
+
+	opt := &SGDOptimizer{}
+	opts := &OptimizerOptions{BatchSize: 4, LearningRate: 0.05, Epochs: 2000, Lambda: 0}
+	if err := opt.Optimize(rg, opts); err != nil {
+		t.Fatalf("Optimize returned an error: %v", err)
+	}
+
+	finalCost, _, _ := rg.CostFunction(0, false)
+	if finalCost >= initialCost {
+		t.Fatalf("SGDOptimizer did not reduce the cost: initial=%v final=%v", initialCost, finalCost)
+	}
+	if finalCost > 0.01 {
+		t.Fatalf("SGDOptimizer left cost at %v, want it close to 0 on an exactly-fittable problem", finalCost)
+	}
+}
+
+// TestSGDOptimizerAppliesL1Ratio guards against splitBatches dropping
+// L1Ratio, which would silently reset elastic-net training back to pure
+// ridge (L1Ratio 0) on every mini-batch
+func TestSGDOptimizerAppliesL1Ratio(t *testing.T) {
+	newRg := func(l1Ratio float64) *Regression {
+		return &Regression{
+			X:         [][]float64{{1, 1}, {1, 2}, {1, 3}, {1, 4}},
+			Y:         []float64{3, 5, 7, 9}, // y = 2x + 1
+			Theta:     []float64{0, 0},
+			LinearReg: true,
+			L1Ratio:   l1Ratio,
+		}
+	}
+	opts := &OptimizerOptions{BatchSize: 2, LearningRate: 0.01, Epochs: 50, Lambda: 50}
+
+	ridge := newRg(0)
+	if err := (&SGDOptimizer{}).Optimize(ridge, opts); err != nil {
+		t.Fatalf("Optimize returned an error: %v", err)
+	}
+
+	lasso := newRg(1)
+	if err := (&SGDOptimizer{}).Optimize(lasso, opts); err != nil {
+		t.Fatalf("Optimize returned an error: %v", err)
+	}
+
+	if math.Abs(lasso.Theta[1]) >= math.Abs(ridge.Theta[1]) {
+		t.Fatalf("heavy L1Ratio lasso training (theta=%v) did not shrink Theta relative to L1Ratio=0 ridge training (theta=%v); L1Ratio is likely being dropped on the per-batch Regression", lasso.Theta, ridge.Theta)
+	}
+}
+
+// TestAdamOptimizerFitsLinearRegression mirrors the SGD test for Adam
+func TestAdamOptimizerFitsLinearRegression(t *testing.T) {
+	rg := &Regression{
+		X:         [][]float64{{1, 1}, {1, 2}, {1, 3}, {1, 4}},
+		Y:         []float64{3, 5, 7, 9}, // y = 2x + 1
+		Theta:     []float64{0, 0},
+		LinearReg: true,
+	}
+
+	opt := &AdamOptimizer{}
+	opts := &OptimizerOptions{BatchSize: 4, LearningRate: 0.1, Epochs: 2000, Lambda: 0}
+	if err := opt.Optimize(rg, opts); err != nil {
+		t.Fatalf("Optimize returned an error: %v", err)
+	}
+
+	finalCost, _, _ := rg.CostFunction(0, false)
+	if finalCost > 0.01 {
+		t.Fatalf("AdamOptimizer left cost at %v, want it close to 0 on an exactly-fittable problem", finalCost)
+	}
+}
@@ -3,11 +3,8 @@ package ml
 import (
 	"fmt"
 	"github.com/alonsovidales/go_matrix"
-	"io/ioutil"
 	"math"
 	"math/rand"
-	"strconv"
-	"strings"
 	"time"
 )
 
@@ -18,6 +15,19 @@ type Regression struct {
 	// 1st dim -> layer, 2nd dim -> neuron, 3rd dim theta
 	Theta     []float64
 	LinearReg bool // true indicates that this is a linear regression problem, false a logistic regression one
+	// Scaler, when set by FitScaler, records the parameters X was scaled
+	// with. Rows of X are pre-scaled; new raw samples must be passed
+	// through PrepareInput before LinearHipotesis/LogisticHipotesis
+	Scaler *FeatureScaler
+	// L1Ratio controls the elastic-net mixing between L2 and L1
+	// regularization: 0 is plain ridge (L2 only), 1 is plain lasso (L1
+	// only), anything in between blends both
+	L1Ratio float64
+	// Optimizer, when set, is used by MinimizeCost/KFoldCV to train instead
+	// of the conjugate-gradient Fmincg loop. OptimizerOpts supplies its
+	// configuration; Lambda, Epochs and Verbose on it are overridden per call
+	Optimizer     Optimizer
+	OptimizerOpts *OptimizerOptions
 }
 
 // CostFunction Calcualtes the cost function for the training set stored in the
@@ -83,10 +93,19 @@ func (rg *Regression) linearRegCostFunction(lambda float64, calcGrad bool) (j fl
 
 	pred := mt.Trans(mt.Mult(rg.X, mt.Trans(theta)))
 	errors := mt.SumAll(mt.Apply(mt.Sub(pred, y), powTwo)) / (2 * m)
-	regTerm := (lambda / (2 * m)) * mt.SumAll(mt.Apply([][]float64{rg.Theta[1:]}, powTwo))
 
-	j = errors + regTerm
-	grad = [][][]float64{mt.Sum(mt.MultBy(mt.Mult(mt.Sub(pred, y), rg.X), 1/m), mt.MultBy(theta, lambda/m))}
+	regSum := 0.0
+	for _, t := range auxTheta[1:] {
+		regSum += (1-rg.L1Ratio)/2*t*t + rg.L1Ratio*math.Abs(t)
+	}
+
+	j = errors + (lambda/m)*regSum
+
+	gradMain := mt.MultBy(mt.Mult(mt.Sub(pred, y), rg.X), 1/m)
+	for idx := 1; idx < len(auxTheta); idx++ {
+		gradMain[0][idx] += (lambda / m) * ((1-rg.L1Ratio)*auxTheta[idx] + rg.L1Ratio*sign(auxTheta[idx]))
+	}
+	grad = [][][]float64{gradMain}
 
 	return
 }
@@ -101,32 +120,21 @@ func (rg *Regression) linearRegCostFunction(lambda float64, calcGrad bool) (j fl
 //      XN1 XN2 ... XNN YN
 //
 // Note: Use a single space as separator
+//
+// Deprecated: LoadFile panics on the first parse error and reads the whole
+// file into memory; use DataLoader.Load (typed errors, configurable
+// delimiter/missing values) or DataLoader.StreamBatches (bounded memory)
+// instead. LoadFile is kept only for existing single-space-separated
+// callers and is now a thin, panicking wrapper around DataLoader.Load
 func LoadFile(filePath string) (rg *Regression) {
-	strInfo, err := ioutil.ReadFile(filePath)
+	dl := NewDataLoader(DataLoaderOptions{Delimiter: ' ', TargetColumn: -1})
+
+	rg, err := dl.Load(filePath)
 	if err != nil {
 		panic(err)
 	}
-	rg = new(Regression)
 
-	trainingData := strings.Split(string(strInfo), "\n")
-	for _, line := range trainingData {
-		if line == "" {
-			break
-		}
-
-		var values []float64
-		for _, value := range strings.Split(line, " ") {
-			floatVal, err := strconv.ParseFloat(value, 64)
-			if err != nil {
-				panic(err)
-			}
-			values = append(values, floatVal)
-		}
-		rg.X = append(rg.X, values[:len(values)-1])
-		rg.Y = append(rg.Y, values[len(values)-1])
-	}
-
-	return
+	return rg
 }
 
 // LogisticHipotesis returns the hipotesis result for Logistic Regression for
@@ -155,92 +163,119 @@ func (rg *Regression) logisticRegCostFunction(lambda float64, calcGrad bool) (j
 	j = (mt.Mult(mt.Apply(y, neg), mt.Trans(mt.Apply(hx, math.Log)))[0][0] -
 		mt.Mult(mt.Apply(y, oneMinus), mt.Trans(mt.Apply(mt.Apply(hx, oneMinus), math.Log)))[0][0]) / m
 
-	// Regularization
-	theta[0][0] = 0
-	j += lambda / (2 * m) * mt.SumAll(mt.Apply(theta, powTwo))
+	// Regularization, elastic-net mix between L2 and L1 on every non-bias theta
+	regSum := 0.0
+	for _, t := range auxTheta[1:] {
+		regSum += (1-rg.L1Ratio)/2*t*t + rg.L1Ratio*math.Abs(t)
+	}
+	j += (lambda / m) * regSum
 
 	// Gradient calc
 	gradAux := mt.MultBy(mt.Mult(mt.Sub(hx, y), rg.X), 1/m)
-	grad = [][][]float64{mt.Sum(gradAux, mt.MultBy(theta, lambda/m))}
+	for idx := 1; idx < len(auxTheta); idx++ {
+		gradAux[0][idx] += (lambda / m) * ((1-rg.L1Ratio)*auxTheta[idx] + rg.L1Ratio*sign(auxTheta[idx]))
+	}
+	grad = [][][]float64{gradAux}
 
 	return
 }
 
+// Accuracy returns the fraction of correctly classified test cases in
+// rg.X/rg.Y under a 0.5 threshold, counting both positive and negative
+// matches (tp+tn)/(tp+fp+tn+fn), 0 when rg.X is empty
 func (rg *Regression) Accuracy() float64 {
-	m := len(rg.X)
-	correct := 0.0
-
-	for i := 0; i < m; i++ {
-		x := rg.X[i]
-		y := rg.Y[i]
-		h := rg.LogisticHipotesis(x)
-
-		if h >= 0.5 && y == 1 {
-			correct++
-		}
+	tp, fp, tn, fn := rg.confusionCounts()
+	total := tp + fp + tn + fn
+	if total == 0 {
+		return 0
 	}
 
-	return correct / float64(m)
+	return (tp + tn) / total
 }
 
 // MinimizeCost this metod splits the given data in three sets: training, cross
 // validation, test. In order to calculate the optimal theta, tries with
 // different possibilities and the training data, and check the best match with
-// the cross validations, after obtain the best lambda, check the perfomand
-// against the test set of data
+// the cross validations, after obtain the best lambda, retrains on
+// training+cross-validation and reports the final cost against the held-out
+// test set of data
 func (rg *Regression) MinimizeCost(maxIters int, suffleData bool, verbose bool) (finalCost float64, trainingData *Regression, lambda float64, testData *Regression) {
 	lambdas := []float64{0.0, 0.001, 0.003, 0.01, 0.03, 0.1, 0.3, 1, 3, 10, 30, 100, 300}
 
+	data := rg
 	if suffleData {
-		rg = rg.shuffle()
+		data = rg.shuffle()
 	}
 
 	// Get the 60% of the data as training data, 20% as cross validation, and
 	// the remaining 20% as test data
+	m := len(data.X)
+	trainEnd := int(float64(m) * 0.6)
+	cvEnd := trainEnd + int(float64(m)*0.2)
 
 	trainingData = &Regression{
-		X:         rg.X,
-		Y:         rg.Y,
-		Theta:     rg.Theta,
+		X:             data.X[:trainEnd],
+		Y:             data.Y[:trainEnd],
+		Theta:         make([]float64, len(rg.Theta)),
+		LinearReg:     rg.LinearReg,
+		L1Ratio:       rg.L1Ratio,
+		Optimizer:     rg.Optimizer,
+		OptimizerOpts: rg.OptimizerOpts,
+	}
+	cvData := &Regression{
+		X:         data.X[trainEnd:cvEnd],
+		Y:         data.Y[trainEnd:cvEnd],
+		LinearReg: rg.LinearReg,
+	}
+	testData = &Regression{
+		X:         data.X[cvEnd:],
+		Y:         data.Y[cvEnd:],
 		LinearReg: rg.LinearReg,
+		L1Ratio:   rg.L1Ratio,
 	}
 
 	// Launch a process for each lambda in order to obtain the one with best
-	// performance
-	bestJ := math.Inf(1)
-	bestA := 0.0
+	// performance against the cross validation set. score() reports
+	// accuracy for logistic regression and R-squared for linear regression,
+	// so this comparison is meaningful for either mode
+	bestScore := math.Inf(-1)
 	bestLambda := 0.0
 	initTheta := make([]float64, len(trainingData.Theta))
-	copy(initTheta, trainingData.Theta)
 
 	for _, posLambda := range lambdas {
 		if verbose {
 			fmt.Println("Checking Lambda:", posLambda)
 		}
 		copy(trainingData.Theta, initTheta)
-		Fmincg(trainingData, posLambda, 10, verbose)
-
-		j, _, _ := trainingData.CostFunction(posLambda, false)
-
-		if bestJ > j {
-			bestJ = j
-			// bestLambda = posLambda
-		}
+		fit(trainingData, posLambda, maxIters, verbose)
 
-		accuracy := trainingData.Accuracy()
-		if accuracy > bestA {
-			bestA = accuracy
+		cvData.Theta = trainingData.Theta
+		score := cvData.score()
+		if score > bestScore {
+			bestScore = score
 			bestLambda = posLambda
 		}
 	}
 
+	lambda = bestLambda
+
 	// Include the cross validation cases into the training for the final train
-	Fmincg(trainingData, bestLambda, maxIters, verbose)
+	combined := &Regression{
+		X:             append(append([][]float64{}, trainingData.X...), cvData.X...),
+		Y:             append(append([]float64{}, trainingData.Y...), cvData.Y...),
+		Theta:         make([]float64, len(initTheta)),
+		LinearReg:     rg.LinearReg,
+		L1Ratio:       rg.L1Ratio,
+		Optimizer:     rg.Optimizer,
+		OptimizerOpts: rg.OptimizerOpts,
+	}
+	fit(combined, lambda, maxIters, verbose)
 
-	rg.Theta = trainingData.Theta
+	rg.Theta = combined.Theta
+	trainingData.Theta = combined.Theta
+	testData.Theta = combined.Theta
 
-	finalCost, _, _ = trainingData.CostFunction(bestLambda, false)
-	bestLambda = bestLambda
+	finalCost, _, _ = testData.CostFunction(lambda, false)
 
 	return
 }
@@ -277,6 +312,11 @@ func (rg *Regression) shuffle() (shuffledData *Regression) {
 	}
 
 	shuffledData.Theta = rg.Theta
+	shuffledData.LinearReg = rg.LinearReg
+	shuffledData.L1Ratio = rg.L1Ratio
+	shuffledData.Scaler = rg.Scaler
+	shuffledData.Optimizer = rg.Optimizer
+	shuffledData.OptimizerOpts = rg.OptimizerOpts
 
 	return
 }
@@ -0,0 +1,64 @@
+package ml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestShufflePreservesMode guards against shuffle() dropping LinearReg (and
+// L1Ratio/Scaler), which made KFoldCV silently score/train linear-regression
+// folds as logistic regression
+func TestShufflePreservesMode(t *testing.T) {
+	rg := &Regression{
+		X:         [][]float64{{1, 1}, {1, 2}, {1, 3}},
+		Y:         []float64{3, 5, 7},
+		LinearReg: true,
+		L1Ratio:   0.5,
+	}
+
+	shuffled := rg.shuffle()
+
+	if !shuffled.LinearReg {
+		t.Fatal("shuffle() did not preserve LinearReg")
+	}
+	if shuffled.L1Ratio != rg.L1Ratio {
+		t.Fatalf("shuffle() L1Ratio = %v, want %v", shuffled.L1Ratio, rg.L1Ratio)
+	}
+}
+
+// TestLoadFileParsesSpaceSeparatedRows guards LoadFile's now-delegated
+// implementation against regressing on its documented single-space format
+func TestLoadFileParsesSpaceSeparatedRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(path, []byte("1 2 3\n4 5 6\n"), 0644); err != nil {
+		t.Fatalf("unable to write fixture: %v", err)
+	}
+
+	rg := LoadFile(path)
+
+	wantX := [][]float64{{1, 2}, {4, 5}}
+	wantY := []float64{3, 6}
+	for i := range wantX {
+		if rg.X[i][0] != wantX[i][0] || rg.X[i][1] != wantX[i][1] || rg.Y[i] != wantY[i] {
+			t.Fatalf("row %d = (%v, %v), want (%v, %v)", i, rg.X[i], rg.Y[i], wantX[i], wantY[i])
+		}
+	}
+}
+
+// TestLoadFilePanicsOnParseError guards LoadFile's documented panicking
+// contract, now delegated through DataLoader.Load
+func TestLoadFilePanicsOnParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	if err := os.WriteFile(path, []byte("1 2 notanumber\n"), 0644); err != nil {
+		t.Fatalf("unable to write fixture: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("LoadFile on a malformed row did not panic")
+		}
+	}()
+
+	LoadFile(path)
+}
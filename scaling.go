@@ -0,0 +1,191 @@
+package ml
+
+import "math"
+
+// ScaleMethod selects the normalization strategy applied by a FeatureScaler
+type ScaleMethod int
+
+const (
+	// MeanNormalization scales each feature to (x-mean)/stddev
+	MeanNormalization ScaleMethod = iota
+	// MinMaxScaling scales each feature to the [0, 1] range
+	MinMaxScaling
+	// UnitNormScaling scales each test case to a unit-length vector
+	UnitNormScaling
+)
+
+// FeatureScaler learns per-feature scaling parameters from a training set
+// and applies them consistently to any other set, so a Regression trained on
+// scaled data can transparently scale new inputs at prediction time
+type FeatureScaler struct {
+	Method ScaleMethod
+	Mean   []float64
+	StdDev []float64
+	Min    []float64
+	Max    []float64
+}
+
+// NewFeatureScaler returns a FeatureScaler configured to use the given method
+func NewFeatureScaler(method ScaleMethod) *FeatureScaler {
+	return &FeatureScaler{Method: method}
+}
+
+// Fit learns the scaling parameters for the given training set
+func (fs *FeatureScaler) Fit(X [][]float64) {
+	if len(X) == 0 {
+		return
+	}
+
+	numFeatures := len(X[0])
+
+	switch fs.Method {
+	case MeanNormalization:
+		fs.Mean = make([]float64, numFeatures)
+		fs.StdDev = make([]float64, numFeatures)
+
+		for _, row := range X {
+			for j, v := range row {
+				fs.Mean[j] += v
+			}
+		}
+		for j := range fs.Mean {
+			fs.Mean[j] /= float64(len(X))
+		}
+
+		for _, row := range X {
+			for j, v := range row {
+				d := v - fs.Mean[j]
+				fs.StdDev[j] += d * d
+			}
+		}
+		for j := range fs.StdDev {
+			fs.StdDev[j] = math.Sqrt(fs.StdDev[j] / float64(len(X)))
+			if fs.StdDev[j] == 0 {
+				fs.StdDev[j] = 1
+			}
+		}
+	case MinMaxScaling:
+		fs.Min = make([]float64, numFeatures)
+		fs.Max = make([]float64, numFeatures)
+		copy(fs.Min, X[0])
+		copy(fs.Max, X[0])
+
+		for _, row := range X {
+			for j, v := range row {
+				if v < fs.Min[j] {
+					fs.Min[j] = v
+				}
+				if v > fs.Max[j] {
+					fs.Max[j] = v
+				}
+			}
+		}
+	}
+}
+
+// Transform applies the learned scaling parameters to X, returning a new matrix
+func (fs *FeatureScaler) Transform(X [][]float64) [][]float64 {
+	result := make([][]float64, len(X))
+
+	for i, row := range X {
+		newRow := make([]float64, len(row))
+
+		switch fs.Method {
+		case MeanNormalization:
+			for j, v := range row {
+				newRow[j] = (v - fs.Mean[j]) / fs.StdDev[j]
+			}
+		case MinMaxScaling:
+			for j, v := range row {
+				span := fs.Max[j] - fs.Min[j]
+				if span == 0 {
+					span = 1
+				}
+				newRow[j] = (v - fs.Min[j]) / span
+			}
+		case UnitNormScaling:
+			norm := 0.0
+			for _, v := range row {
+				norm += v * v
+			}
+			norm = math.Sqrt(norm)
+			if norm == 0 {
+				norm = 1
+			}
+			for j, v := range row {
+				newRow[j] = v / norm
+			}
+		}
+
+		result[i] = newRow
+	}
+
+	return result
+}
+
+// InverseTransform reverses Transform for MeanNormalization and MinMaxScaling.
+// UnitNormScaling discards the original magnitude, so it is not invertible
+// and the input is returned unchanged
+func (fs *FeatureScaler) InverseTransform(X [][]float64) [][]float64 {
+	result := make([][]float64, len(X))
+
+	for i, row := range X {
+		newRow := make([]float64, len(row))
+
+		switch fs.Method {
+		case MeanNormalization:
+			for j, v := range row {
+				newRow[j] = v*fs.StdDev[j] + fs.Mean[j]
+			}
+		case MinMaxScaling:
+			for j, v := range row {
+				newRow[j] = v*(fs.Max[j]-fs.Min[j]) + fs.Min[j]
+			}
+		default:
+			copy(newRow, row)
+		}
+
+		result[i] = newRow
+	}
+
+	return result
+}
+
+// AddBiasColumn prepends a column of ones to X, the bias term that
+// CostFunction expects at Theta[0] and special-cases out of regularization.
+// This removes the need for callers to hand-craft the leading 1 column
+func AddBiasColumn(X [][]float64) [][]float64 {
+	result := make([][]float64, len(X))
+
+	for i, row := range X {
+		newRow := make([]float64, len(row)+1)
+		newRow[0] = 1
+		copy(newRow[1:], row)
+		result[i] = newRow
+	}
+
+	return result
+}
+
+// FitScaler fits a FeatureScaler of the given method on rg.X, stores it on
+// the instance, and replaces rg.X with the scaled features plus a prepended
+// bias column. rg.X rows are scaled exactly once by this call; callers
+// predicting on new, raw samples afterwards must scale them the same way
+// with PrepareInput before passing them to LinearHipotesis/LogisticHipotesis
+func (rg *Regression) FitScaler(method ScaleMethod) {
+	rg.Scaler = NewFeatureScaler(method)
+	rg.Scaler.Fit(rg.X)
+	rg.X = AddBiasColumn(rg.Scaler.Transform(rg.X))
+}
+
+// PrepareInput scales a raw feature vector x with rg.Scaler and prepends the
+// bias column, producing the same row shape as rg.X after FitScaler. Rows
+// already taken from rg.X are pre-scaled and must NOT be passed through this
+// again. When no scaler was fit, it only prepends the bias column
+func (rg *Regression) PrepareInput(x []float64) []float64 {
+	if rg.Scaler != nil {
+		x = rg.Scaler.Transform([][]float64{x})[0]
+	}
+
+	return AddBiasColumn([][]float64{x})[0]
+}
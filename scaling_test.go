@@ -0,0 +1,40 @@
+package ml
+
+import (
+	"math"
+	"testing"
+)
+
+// TestFitScalerNoDoubleScaling guards against scaling rg.X once at fit time
+// and then scaling it again inside LinearHipotesis/LogisticHipotesis
+func TestFitScalerNoDoubleScaling(t *testing.T) {
+	rg := &Regression{
+		X:         [][]float64{{1}, {2}, {3}},
+		Y:         []float64{1, 2, 3},
+		LinearReg: true,
+	}
+	rg.FitScaler(MeanNormalization)
+	rg.Theta = []float64{0, 1}
+
+	want := rg.X[0][1]
+	got := rg.LinearHipotesis(rg.X[0])
+	if math.Abs(got-want) > 1e-9 {
+		t.Fatalf("LinearHipotesis(rg.X[0]) = %v, want %v (rg.X rows must not be rescaled)", got, want)
+	}
+}
+
+// TestPrepareInputMatchesFitScaler checks that a raw sample fed through
+// PrepareInput lands on the same row FitScaler produced for the same input
+func TestPrepareInputMatchesFitScaler(t *testing.T) {
+	raw := [][]float64{{1}, {2}, {3}}
+
+	rg := &Regression{X: append([][]float64{}, raw...), LinearReg: true}
+	rg.FitScaler(MeanNormalization)
+
+	prepared := rg.PrepareInput(raw[1])
+	for i := range prepared {
+		if math.Abs(prepared[i]-rg.X[1][i]) > 1e-9 {
+			t.Fatalf("PrepareInput(raw[1]) = %v, want %v", prepared, rg.X[1])
+		}
+	}
+}